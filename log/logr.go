@@ -16,34 +16,157 @@ package log
 
 import (
 	"fmt"
+	"log/slog"
 
 	"github.com/go-logr/logr"
 )
 
-// zapLogger is a logr.Logger that uses Zap to log. This is needed to get
+// missingValueKey is the key paired with a dangling value when keysAndVals
+// has an odd length, mirroring log/slog's own "!BADKEY" convention so a
+// mismatched call never silently drops data.
+const missingValueKey = "!BADKEY"
+
+// flattenKeysAndValues walks keysAndVals in (key, value) pairs, resolving
+// logr.Marshaler/slog.LogValuer/fmt.Stringer values and expanding
+// slog.Group values, and returns a flat slice of alternating keys and
+// resolved values suitable for Scope.WithLabels. An odd trailing element is
+// paired with missingValueKey rather than dropped.
+func flattenKeysAndValues(keysAndVals []interface{}) []interface{} {
+	out := make([]interface{}, 0, len(keysAndVals))
+	for i := 0; i < len(keysAndVals); i += 2 {
+		if i+1 >= len(keysAndVals) {
+			out = append(out, missingValueKey, keysAndVals[i])
+			break
+		}
+		out = append(out, keysAndVals[i], resolveValue(keysAndVals[i+1]))
+	}
+	return out
+}
+
+// resolveValue expands logr.Marshaler and slog.LogValuer/slog.Value values
+// (recursively: a group becomes a map, and a group nested in a group
+// becomes a nested map), and stringifies fmt.Stringer values, so structured fields come out
+// as plain, encodable values regardless of which logging facade produced
+// them.
+func resolveValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case logr.Marshaler:
+		return resolveValue(t.MarshalLog())
+	case slog.LogValuer:
+		return resolveValue(t.LogValue())
+	case slog.Value:
+		return resolveSlogValue(t)
+	case fmt.Stringer:
+		return t.String()
+	default:
+		return v
+	}
+}
+
+func resolveSlogValue(v slog.Value) interface{} {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return resolveValue(v.Any())
+	}
+	group := v.Group()
+	m := make(map[string]interface{}, len(group))
+	for _, a := range group {
+		m[a.Key] = resolveSlogValue(a.Value)
+	}
+	return m
+}
+
+// zapLogger is a logr.LogSink that logs through a Scope. This is needed to get
 // libraries, namely Kubernetes/klog, that use logr, to use our standard logging.
 // This enables standard formatting, scope filtering, and options. The logr
 // interface does not have a concept of Debug/Info/Warn/Error as we do. Instead,
-// logging is based on Verbosity levels, where 0 is the most important. We treat
-// levels 0-3 as info level and 4+ as debug; there are no warnings. This
-// threshold is fairly arbitrary based on inspection of Kubernetes usage and
+// logging is based on Verbosity levels, where 0 is the most important. By
+// default we treat levels 0-3 as info level and 4+ as debug; there are no
+// warnings. This threshold is fairly arbitrary based on inspection of
+// Kubernetes usage and
 // https://kubernetes.io/docs/reference/kubectl/cheatsheet/#kubectl-output-verbosity-and-debugging.
 // Errors are passed through as errors.
 // Zap does come with its own logr implementation, but we have chosen to re-implement to allow usage of
 // our Scope - in particular, this allows changing the logging level of kubernetes logs by users.
+//
+// zapLogger implements the modern logr.LogSink interface (logr v1+) rather
+// than the legacy logr.Logger interface; NewLogrAdapter wraps it with
+// logr.New to produce a usable logr.Logger.
 type zapLogger struct {
-	l      *Scope
-	lvl    int
-	lvlSet bool
+	l    *Scope
+	name string
+	opts LogrOptions
 }
 
+var _ logr.LogSink = (*zapLogger)(nil)
+
 const debugLevelThreshold = 3
 
-func (zl *zapLogger) Enabled() bool {
-	if zl.lvlSet && zl.lvl > debugLevelThreshold {
+// LogrOptions controls how logr.Logger verbosity levels (V(n)) are mapped
+// onto this package's levels. Without any options set, the
+// behavior matches the historical default: V(0-3) is Info, V(4+) is Debug.
+type LogrOptions struct {
+	// Threshold is the highest V-level that is still considered Info; any
+	// higher V-level maps to Debug. Ignored if Mapping is set. Defaults to
+	// debugLevelThreshold when zero.
+	Threshold int
+
+	// Mapping, if non-nil, takes precedence over Threshold and maps a
+	// V-level directly to a Level. The full range of Levels is honored, not
+	// just Info/Debug: e.g. returning WarnLevel or ErrorLevel from Mapping
+	// surfaces that record at the matching Scope severity. There is no
+	// level below DebugLevel, so there is no separate "trace" bucket: every
+	// V-level mapped to DebugLevel shares the Scope's Debug gating.
+	Mapping func(v int) Level
+
+	// ScopeOverrides allows individual Scopes, keyed by Scope name, to use a
+	// different Threshold/Mapping than the default passed to
+	// NewLogrAdapter.
+	ScopeOverrides map[string]LogrOptions
+}
+
+// optionsFor returns the effective LogrOptions for the given scope, applying
+// any ScopeOverrides.
+func (o LogrOptions) optionsFor(scope *Scope) LogrOptions {
+	if scope == nil {
+		return o
+	}
+	if override, ok := o.ScopeOverrides[scope.Name()]; ok {
+		return override
+	}
+	return o
+}
+
+// mappedLevel returns the Level that logr V-level v maps onto.
+func (zl *zapLogger) mappedLevel(v int) Level {
+	opts := zl.opts.optionsFor(zl.l)
+	if opts.Mapping != nil {
+		return opts.Mapping(v)
+	}
+	threshold := opts.Threshold
+	if threshold == 0 {
+		threshold = debugLevelThreshold
+	}
+	if v > threshold {
+		return DebugLevel
+	}
+	return InfoLevel
+}
+
+// Init receives optional information about the logr library caller.
+func (zl *zapLogger) Init(_ logr.RuntimeInfo) {}
+
+func (zl *zapLogger) Enabled(level int) bool {
+	switch zl.mappedLevel(level) {
+	case ErrorLevel:
+		return zl.l.ErrorEnabled()
+	case WarnLevel:
+		return zl.l.WarnEnabled()
+	case DebugLevel:
 		return zl.l.DebugEnabled()
+	default:
+		return zl.l.InfoEnabled()
 	}
-	return zl.l.InfoEnabled()
 }
 
 // Logs will come in with newlines, but our logger auto appends newline
@@ -58,45 +181,91 @@ func trimNewline(msg string) string {
 	return msg
 }
 
-func (zl *zapLogger) Info(msg string, keysAndVals ...interface{}) {
-	if zl.lvlSet && zl.lvl > debugLevelThreshold {
-		zl.l.Debug(trimNewline(msg), keysAndVals)
-	} else {
-		zl.l.Info(trimNewline(msg), keysAndVals)
+// withFields returns a Scope with keysAndVals merged in as labels, flattened
+// and resolved into plain key/value pairs. It returns zl.l unchanged if
+// there are no fields to add.
+func (zl *zapLogger) withFields(keysAndVals []interface{}) *Scope {
+	if len(keysAndVals) == 0 {
+		return zl.l
 	}
+	return zl.l.WithLabels(flattenKeysAndValues(keysAndVals)...)
 }
 
-func (zl *zapLogger) Error(err error, msg string, keysAndVals ...interface{}) {
-	if zl.l.ErrorEnabled() {
-		if err == nil {
-			zl.l.Error(trimNewline(msg), keysAndVals)
-		} else {
-			zl.l.Error(fmt.Sprintf("%v: %s", err.Error(), msg), keysAndVals)
-		}
+func (zl *zapLogger) Info(level int, msg string, keysAndVals ...interface{}) {
+	l := zl.withFields(keysAndVals)
+	msg = trimNewline(msg)
+	switch zl.mappedLevel(level) {
+	case ErrorLevel:
+		l.Error(msg)
+	case WarnLevel:
+		l.Warn(msg)
+	case DebugLevel:
+		l.Debug(msg)
+	default:
+		l.Info(msg)
 	}
 }
 
-func (zl *zapLogger) V(level int) logr.Logger {
-	return &zapLogger{
-		lvl:    zl.lvl + level,
-		l:      zl.l,
-		lvlSet: true,
+func (zl *zapLogger) Error(err error, msg string, keysAndVals ...interface{}) {
+	if !zl.l.ErrorEnabled() {
+		return
 	}
+	l := zl.withFields(keysAndVals)
+	if err == nil {
+		l.Error(trimNewline(msg))
+	} else {
+		l.Error(fmt.Sprintf("%v: %s", err.Error(), msg))
+	}
+}
+
+func (zl *zapLogger) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	cp := *zl
+	cp.l = zl.withFields(keysAndValues)
+	return &cp
 }
 
-func (zl *zapLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
-	return newLogrAdapter(zl.l.WithLabels(keysAndValues...))
+// WithName chains name onto any existing name with a ".", matching logr's
+// convention, and attaches the accumulated name to the Scope as a label so
+// it shows up in the emitted record.
+func (zl *zapLogger) WithName(name string) logr.LogSink {
+	cp := *zl
+	if cp.name == "" {
+		cp.name = name
+	} else {
+		cp.name = cp.name + "." + name
+	}
+	cp.l = zl.l.WithLabels("logger", cp.name)
+	return &cp
 }
 
-func (zl *zapLogger) WithName(name string) logr.Logger {
-	return zl
+var _ logr.CallDepthLogSink = (*zapLogger)(nil)
+
+// WithCallDepth returns a LogSink that offsets the depth of the call stack
+// by the specified number of additional frames, implementing
+// logr.CallDepthLogSink. This is what lets helper wrappers built on top of
+// this adapter (e.g. a package that wraps every call in its own logging
+// function) still have the Scope report the real caller rather than the wrapper.
+func (zl *zapLogger) WithCallDepth(depth int) logr.LogSink {
+	cp := *zl
+	cp.l = zl.l.WithCallDepth(depth)
+	return &cp
 }
 
-// NewLogger creates a new logr.Logger using the given Zap Logger to log.
+// callerSkipForLogrAdapter accounts for the frames added by this adapter
+// itself (the LogSink method plus logr.Logger's own dispatch) so that,
+// absent any further WithCallDepth calls, records point at the logr/klog/
+// slog call site rather than at logr.go.
+const callerSkipForLogrAdapter = 2
+
+// newLogrAdapter creates a new logr.Logger using the given Scope to log.
 func newLogrAdapter(l *Scope) logr.Logger {
-	return &zapLogger{
-		l:      l,
-		lvl:    0,
-		lvlSet: false,
-	}
+	return NewLogrAdapter(l, LogrOptions{})
+}
+
+// NewLogrAdapter creates a new logr.Logger backed by the given Scope. opts
+// controls how logr's V-level verbosity maps onto the Scope's levels; the
+// zero value reproduces the historical V(0-3)=Info, V(4+)=Debug
+// behavior.
+func NewLogrAdapter(scope *Scope, opts LogrOptions) logr.Logger {
+	return logr.New(&zapLogger{l: scope.WithCallDepth(callerSkipForLogrAdapter), opts: opts})
 }
@@ -0,0 +1,267 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+var logrBenchScope = RegisterScope("logr_bench", "scope used by logr adapter benchmarks")
+
+type stringerValue struct{ s string }
+
+func (v stringerValue) String() string { return v.s }
+
+type marshalerValue struct{ v interface{} }
+
+func (v marshalerValue) MarshalLog() interface{} { return v.v }
+
+// logrErrMarshaler is a logr.Marshaler whose resolved value is itself a
+// fmt.Stringer, exercising the recursive resolution path.
+type logrErrMarshaler struct{ err error }
+
+func (m logrErrMarshaler) MarshalLog() interface{} { return stringerValue{m.err.Error()} }
+
+var _ logr.Marshaler = logrErrMarshaler{}
+
+type logValuerValue struct{ v slog.Value }
+
+func (v logValuerValue) LogValue() slog.Value { return v.v }
+
+func TestFlattenKeysAndValues(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []interface{}
+		want []interface{}
+	}{
+		{
+			name: "empty",
+			in:   nil,
+			want: []interface{}{},
+		},
+		{
+			name: "pairs passthrough",
+			in:   []interface{}{"a", 1, "b", "two"},
+			want: []interface{}{"a", 1, "b", "two"},
+		},
+		{
+			name: "odd tail gets sentinel key",
+			in:   []interface{}{"a", 1, "dangling"},
+			want: []interface{}{"a", 1, missingValueKey, "dangling"},
+		},
+		{
+			name: "stringer value resolved",
+			in:   []interface{}{"k", stringerValue{"hi"}},
+			want: []interface{}{"k", "hi"},
+		},
+		{
+			name: "marshaler value resolved, recursively",
+			in:   []interface{}{"k", marshalerValue{stringerValue{"nested"}}},
+			want: []interface{}{"k", "nested"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := flattenKeysAndValues(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("flattenKeysAndValues(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveValue(t *testing.T) {
+	boom := errors.New("boom")
+
+	tests := []struct {
+		name string
+		in   interface{}
+		want interface{}
+	}{
+		{name: "plain value", in: 42, want: 42},
+		{name: "stringer", in: stringerValue{"hi"}, want: "hi"},
+		{name: "marshaler", in: marshalerValue{"raw"}, want: "raw"},
+		{name: "logr marshaler resolving to a stringer", in: logrErrMarshaler{boom}, want: boom.Error()},
+		{
+			name: "slog log valuer",
+			in:   logValuerValue{slog.StringValue("from-valuer")},
+			want: "from-valuer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveValue(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("resolveValue(%v) = %v (%T), want %v (%T)", tt.in, got, got, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSlogValue(t *testing.T) {
+	group := slog.GroupValue(
+		slog.String("a", "1"),
+		slog.Any("b", slog.GroupValue(slog.Int("c", 2))),
+	)
+
+	got := resolveSlogValue(group)
+	want := map[string]interface{}{
+		"a": "1",
+		"b": map[string]interface{}{"c": int64(2)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("resolveSlogValue(group) = %#v, want %#v", got, want)
+	}
+}
+
+// BenchmarkLogrAdapterInfo exercises the hot path of the logr bridge
+// (key/value flattening and resolution) to keep per-call allocations
+// comparable to zapr.
+func BenchmarkLogrAdapterInfo(b *testing.B) {
+	l := NewLogrAdapter(logrBenchScope, LogrOptions{})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("benchmark message", "key1", "value1", "key2", i, "err", errors.New("boom"))
+	}
+}
+
+func BenchmarkLogrAdapterWithValues(b *testing.B) {
+	l := NewLogrAdapter(logrBenchScope, LogrOptions{}).WithValues("component", "bench")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("benchmark message", "key1", "value1", "key2", i)
+	}
+}
+
+// TestMappedLevelDispatch verifies that Enabled/Info dispatch on the full
+// Level a Mapping returns, not just a Debug/Info binary split.
+func TestMappedLevelDispatch(t *testing.T) {
+	scope := RegisterScope("logr_dispatch_test", "")
+	scope.SetOutputLevel(DebugLevel)
+
+	var got []string
+	scope.UseBackend(scopeBackendFunc(func(level Level, msg string, _ []interface{}) {
+		got = append(got, fmt.Sprintf("%d:%s", level, msg))
+	}))
+
+	l := NewLogrAdapter(scope, LogrOptions{
+		Mapping: func(v int) Level {
+			switch v {
+			case 0:
+				return ErrorLevel
+			case 1:
+				return WarnLevel
+			case 2:
+				return InfoLevel
+			default:
+				return DebugLevel
+			}
+		},
+	})
+
+	l.V(0).Info("err-ish")
+	l.V(1).Info("warn-ish")
+	l.V(2).Info("info-ish")
+	l.V(5).Info("debug-ish")
+
+	want := []string{
+		fmt.Sprintf("%d:err-ish", ErrorLevel),
+		fmt.Sprintf("%d:warn-ish", WarnLevel),
+		fmt.Sprintf("%d:info-ish", InfoLevel),
+		fmt.Sprintf("%d:debug-ish", DebugLevel),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// TestScopeOverridesDispatchIndependently verifies that optionsFor's
+// ScopeOverrides precedence is applied per-scope: two scopes sharing one
+// LogrOptions, each with its own override, must dispatch V-levels according
+// to its own override rather than the shared default or each other's.
+func TestScopeOverridesDispatchIndependently(t *testing.T) {
+	a := RegisterScope("logr_scope_override_test_a", "")
+	b := RegisterScope("logr_scope_override_test_b", "")
+	a.SetOutputLevel(DebugLevel)
+	b.SetOutputLevel(DebugLevel)
+
+	var gotA, gotB []string
+	a.UseBackend(scopeBackendFunc(func(level Level, msg string, _ []interface{}) {
+		gotA = append(gotA, fmt.Sprintf("%d:%s", level, msg))
+	}))
+	b.UseBackend(scopeBackendFunc(func(level Level, msg string, _ []interface{}) {
+		gotB = append(gotB, fmt.Sprintf("%d:%s", level, msg))
+	}))
+
+	shared := LogrOptions{
+		Threshold: 10, // a default so permissive it never maps to Debug
+		ScopeOverrides: map[string]LogrOptions{
+			"logr_scope_override_test_a": {Threshold: 1},
+			"logr_scope_override_test_b": {Threshold: 5},
+		},
+	}
+
+	la := NewLogrAdapter(a, shared)
+	lb := NewLogrAdapter(b, shared)
+
+	la.V(2).Info("a-v2")
+	lb.V(2).Info("b-v2")
+
+	wantA := []string{fmt.Sprintf("%d:a-v2", DebugLevel)} // a's override: Threshold 1, so V(2) > 1 maps to Debug
+	wantB := []string{fmt.Sprintf("%d:b-v2", InfoLevel)}  // b's override: Threshold 5, so V(2) is still Info
+	if !reflect.DeepEqual(gotA, wantA) {
+		t.Fatalf("scope a: got %v, want %v", gotA, wantA)
+	}
+	if !reflect.DeepEqual(gotB, wantB) {
+		t.Fatalf("scope b: got %v, want %v", gotB, wantB)
+	}
+}
+
+// TestWithNameDoesNotDuplicateLoggerLabel verifies that chaining WithName
+// more than once replaces the "logger" label's value rather than appending
+// a second one, relying on Scope.WithLabels deduping by key.
+func TestWithNameDoesNotDuplicateLoggerLabel(t *testing.T) {
+	scope := RegisterScope("logr_with_name_test", "")
+	scope.SetOutputLevel(InfoLevel)
+
+	var got []interface{}
+	scope.UseBackend(scopeBackendFunc(func(_ Level, _ string, keysAndVals []interface{}) {
+		got = keysAndVals
+	}))
+
+	l := NewLogrAdapter(scope, LogrOptions{}).WithName("a").WithName("b")
+	l.Info("msg")
+
+	loggerCount := 0
+	var loggerValue interface{}
+	for i := 0; i+1 < len(got); i += 2 {
+		if got[i] == "logger" {
+			loggerCount++
+			loggerValue = got[i+1]
+		}
+	}
+	if loggerCount != 1 {
+		t.Fatalf("expected exactly one \"logger\" label, got %d in %v", loggerCount, got)
+	}
+	if loggerValue != "a.b" {
+		t.Fatalf("expected logger label %q, got %q", "a.b", loggerValue)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"runtime"
+	"sync"
+)
+
+// Level is the severity of a log record.
+type Level int
+
+const (
+	NoneLevel Level = iota
+	ErrorLevel
+	WarnLevel
+	InfoLevel
+	DebugLevel
+)
+
+// Scope is a named logging context that can be independently enabled,
+// carries a set of structured labels attached to every record it emits, and
+// can have its output redirected to an alternate ScopeBackend (see
+// UseBackend).
+type Scope struct {
+	name string
+
+	mu         sync.RWMutex
+	level      Level
+	callerSkip int
+	labels     []interface{}
+	backend    ScopeBackend
+	writer     io.Writer
+}
+
+var (
+	scopesMu sync.Mutex
+	scopes   = map[string]*Scope{}
+)
+
+// RegisterScope registers a new Scope with the given name and description,
+// defaulting to InfoLevel. Scopes are keyed by name; registering the same
+// name twice returns the already-registered Scope.
+func RegisterScope(name, description string) *Scope {
+	scopesMu.Lock()
+	defer scopesMu.Unlock()
+	if s, ok := scopes[name]; ok {
+		return s
+	}
+	s := &Scope{name: name, level: InfoLevel, writer: os.Stderr}
+	scopes[name] = s
+	return s
+}
+
+// Scopes returns every currently registered Scope.
+func Scopes() []*Scope {
+	scopesMu.Lock()
+	defer scopesMu.Unlock()
+	out := make([]*Scope, 0, len(scopes))
+	for _, s := range scopes {
+		out = append(out, s)
+	}
+	return out
+}
+
+// Name returns the name this Scope was registered under.
+func (s *Scope) Name() string {
+	return s.name
+}
+
+// SetOutputLevel sets the minimum level this Scope emits.
+func (s *Scope) SetOutputLevel(level Level) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.level = level
+}
+
+func (s *Scope) outputLevel() Level {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.level
+}
+
+// enabled reports whether level would be emitted by this Scope. If a
+// backend is installed, its own gating takes over entirely - e.g. a
+// textBackend's Vmodule overrides may allow a level through that s.level
+// alone would not - so the backend, not s.level, is authoritative whenever
+// one is present. skip is passed through so a backend that inspects the
+// caller (Vmodule's per-file rules) sees the real call site.
+func (s *Scope) enabled(level Level) bool {
+	s.mu.RLock()
+	backend := s.backend
+	ownLevel := s.level
+	skip := s.callerSkip
+	s.mu.RUnlock()
+
+	if backend != nil {
+		return backend.Enabled(level, skip)
+	}
+	return level <= ownLevel
+}
+
+func (s *Scope) DebugEnabled() bool { return s.enabled(DebugLevel) }
+func (s *Scope) InfoEnabled() bool  { return s.enabled(InfoLevel) }
+func (s *Scope) WarnEnabled() bool  { return s.enabled(WarnLevel) }
+func (s *Scope) ErrorEnabled() bool { return s.enabled(ErrorLevel) }
+
+// clone returns a copy of s, with its own independent labels slice, so that
+// With* methods can return a derived Scope without mutating the receiver.
+func (s *Scope) clone() *Scope {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return &Scope{
+		name:       s.name,
+		level:      s.level,
+		callerSkip: s.callerSkip,
+		labels:     append([]interface{}{}, s.labels...),
+		backend:    s.backend,
+		writer:     s.writer,
+	}
+}
+
+// WithLabels returns a Scope that attaches keysAndValues, as alternating
+// key/value pairs, to every record it emits, in addition to any labels
+// already present on s. A key already present on s has its value replaced
+// rather than duplicated. A trailing, unpaired key is recorded with a nil
+// value.
+func (s *Scope) WithLabels(keysAndValues ...interface{}) *Scope {
+	cp := s.clone()
+	i := 0
+	for ; i+1 < len(keysAndValues); i += 2 {
+		cp.setLabel(keysAndValues[i], keysAndValues[i+1])
+	}
+	if i < len(keysAndValues) {
+		cp.setLabel(keysAndValues[i], nil)
+	}
+	return cp
+}
+
+func (s *Scope) setLabel(key, value interface{}) {
+	for i := 0; i+1 < len(s.labels); i += 2 {
+		if s.labels[i] == key {
+			s.labels[i+1] = value
+			return
+		}
+	}
+	s.labels = append(s.labels, key, value)
+}
+
+// WithCallDepth returns a Scope that offsets the reported caller by depth
+// additional frames, mirroring logr.CallDepthLogSink so helper wrappers
+// built on top of this package still report the correct caller.
+func (s *Scope) WithCallDepth(depth int) *Scope {
+	cp := s.clone()
+	cp.callerSkip += depth
+	return cp
+}
+
+// UseBackend installs backend in place of this Scope's default writer,
+// bypassing the built-in formatting entirely. Passing a nil backend
+// reverts to the default.
+func (s *Scope) UseBackend(backend ScopeBackend) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backend = backend
+}
+
+// emitCallerSkip accounts for the two frames between callerInfo's caller and
+// the user's call - emit itself and the Scope.Debug/Info/Warn/Error method
+// that called it - so that, absent any WithCallDepth, records
+// written to the default writer are attributed to the Debug/Info/Warn/Error
+// call site rather than to emit or callerInfo.
+const emitCallerSkip = 2
+
+func (s *Scope) emit(level Level, msg string) {
+	s.mu.RLock()
+	backend := s.backend
+	labels := append([]interface{}{}, s.labels...)
+	w := s.writer
+	skip := s.callerSkip
+	s.mu.RUnlock()
+
+	if backend != nil {
+		backend.Log(level, msg, labels, skip)
+		return
+	}
+	file, line := callerInfo(emitCallerSkip + skip)
+	prefix := fmt.Sprintf("%s:%d:", path.Base(file), line)
+	fmt.Fprintln(w, append([]interface{}{prefix, msg}, labels...)...)
+}
+
+// callerInfo returns the file and line skip frames above its own caller,
+// mirroring textlogger.Logger's Enabled/LogDepth convention so the two
+// output paths (the default writer and a ScopeBackend) attribute records
+// consistently.
+func callerInfo(skip int) (file string, line int) {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "???", 0
+	}
+	return file, line
+}
+
+func (s *Scope) Debug(msg string, keysAndVals ...interface{}) {
+	if !s.DebugEnabled() {
+		return
+	}
+	s.WithLabels(keysAndVals...).emit(DebugLevel, msg)
+}
+
+func (s *Scope) Info(msg string, keysAndVals ...interface{}) {
+	if !s.InfoEnabled() {
+		return
+	}
+	s.WithLabels(keysAndVals...).emit(InfoLevel, msg)
+}
+
+func (s *Scope) Warn(msg string, keysAndVals ...interface{}) {
+	if !s.WarnEnabled() {
+		return
+	}
+	s.WithLabels(keysAndVals...).emit(WarnLevel, msg)
+}
+
+func (s *Scope) Error(msg string, keysAndVals ...interface{}) {
+	if !s.ErrorEnabled() {
+		return
+	}
+	s.WithLabels(keysAndVals...).emit(ErrorLevel, msg)
+}
@@ -0,0 +1,65 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// logDirect logs through scope without any extra call depth, so the default
+// writer should attribute the record to the line below.
+func logDirect(scope *Scope) {
+	scope.Info("direct") // logDirectLine
+}
+
+// logViaWrapper mimics a helper built on top of this package: it adds one
+// frame of its own, so it must call WithCallDepth(1) to still attribute the
+// record to its own caller rather than to itself.
+func logViaWrapper(scope *Scope) {
+	scope.WithCallDepth(1).Info("via wrapper")
+}
+
+// TestWithCallDepthAdjustsDefaultWriterCaller proves WithCallDepth actually
+// changes what the default (non-backend) writer path attributes a record
+// to, rather than being silently ignored.
+func TestWithCallDepthAdjustsDefaultWriterCaller(t *testing.T) {
+	scope := RegisterScope("scope_call_depth_test", "")
+	scope.SetOutputLevel(InfoLevel)
+
+	var buf bytes.Buffer
+	scope.writer = &buf
+
+	logDirect(scope)
+	if !strings.Contains(buf.String(), "scope_test.go:") {
+		t.Fatalf("expected direct call to be attributed to this file, got %q", buf.String())
+	}
+
+	buf.Reset()
+	_, _, callLine, _ := runtime.Caller(0)
+	logViaWrapper(scope) // this is callLine+1
+
+	got := buf.String()
+	want := fmt.Sprintf("scope_test.go:%d:", callLine+1)
+	if !strings.Contains(got, want) {
+		t.Fatalf("expected WithCallDepth(1) to attribute the record to the wrapper's caller (%s), got %q", want, got)
+	}
+	if strings.Contains(got, "scope.go") {
+		t.Fatalf("record should never be attributed to scope.go itself, got %q", got)
+	}
+}
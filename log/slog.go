@@ -0,0 +1,122 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// slogHandler adapts a Scope to the slog.Handler interface, so that any code
+// written against log/slog can have its output routed through our
+// Scope-based logging rather than slog's own handlers.
+type slogHandler struct {
+	l      *Scope
+	groups []string
+
+	// fields holds attrs added via WithAttrs, already flattened and
+	// qualified with whatever groups were open at the time WithAttrs was
+	// called. Per the slog.Handler contract, a later WithGroup must not
+	// retroactively re-prefix them, so we can't just keep the raw
+	// []slog.Attr around and re-derive the prefix from h.groups at Handle
+	// time.
+	fields []interface{}
+}
+
+// callerSkipForSlogHandler accounts for the frames added between a user's
+// call and Scope.Debug/Info/Warn/Error: slog.Logger's public level method,
+// its private log dispatch, and slogHandler.Handle itself - so that, absent
+// any further WithCallDepth calls, records point at the caller of
+// slog.Logger.Info (etc.), not at slog.go.
+const callerSkipForSlogHandler = 3
+
+// NewSlogHandler creates an slog.Handler that routes records through scope,
+// translating slog.Level into Scope's Debug/Info/Warn/Error levels.
+func NewSlogHandler(scope *Scope) slog.Handler {
+	return &slogHandler{l: scope.WithCallDepth(callerSkipForSlogHandler)}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	switch {
+	case level >= slog.LevelError:
+		return h.l.ErrorEnabled()
+	case level >= slog.LevelWarn:
+		return h.l.WarnEnabled()
+	case level >= slog.LevelInfo:
+		return h.l.InfoEnabled()
+	default:
+		return h.l.DebugEnabled()
+	}
+}
+
+func (h *slogHandler) Handle(_ context.Context, record slog.Record) error {
+	kv := make([]interface{}, 0, len(h.fields)+record.NumAttrs()*2)
+	kv = append(kv, h.fields...)
+	record.Attrs(func(a slog.Attr) bool {
+		kv = appendSlogAttr(kv, h.groups, a)
+		return true
+	})
+	l := h.l.WithLabels(kv...)
+	switch {
+	case record.Level >= slog.LevelError:
+		l.Error(record.Message)
+	case record.Level >= slog.LevelWarn:
+		l.Warn(record.Message)
+	case record.Level >= slog.LevelInfo:
+		l.Info(record.Message)
+	default:
+		l.Debug(record.Message)
+	}
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	kv := make([]interface{}, 0, len(attrs)*2)
+	for _, a := range attrs {
+		kv = appendSlogAttr(kv, h.groups, a)
+	}
+	cp := *h
+	cp.fields = append(append([]interface{}{}, h.fields...), kv...)
+	return &cp
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	cp := *h
+	cp.groups = append(append([]string{}, h.groups...), name)
+	return &cp
+}
+
+// appendSlogAttr resolves a (expanding LogValuers and Groups), applies any
+// active group prefix to its key, and appends the resulting key/value
+// pair(s) onto kv.
+func appendSlogAttr(kv []interface{}, groups []string, a slog.Attr) []interface{} {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		// groups may be h.groups, shared across concurrent Handle/WithAttrs
+		// calls on the same handler; append onto a fresh copy rather than
+		// risk writing into its backing array in place.
+		nested := append(append([]string{}, groups...), a.Key)
+		for _, ga := range a.Value.Group() {
+			kv = appendSlogAttr(kv, nested, ga)
+		}
+		return kv
+	}
+	key := a.Key
+	if len(groups) > 0 {
+		key = strings.Join(groups, ".") + "." + key
+	}
+	return append(kv, key, resolveValue(a.Value.Any()))
+}
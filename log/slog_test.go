@@ -0,0 +1,125 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSlogHandlerCallerAttribution proves NewSlogHandler offsets the
+// caller by the frames slog.Logger's own dispatch adds, so a record logged
+// through a real slog.Logger - not a direct Handle call - is attributed to
+// the caller of Info, not to slog.go itself.
+func TestSlogHandlerCallerAttribution(t *testing.T) {
+	scope := RegisterScope("slog_caller_attribution_test", "")
+	scope.SetOutputLevel(InfoLevel)
+
+	var buf bytes.Buffer
+	scope.writer = &buf
+
+	_, _, callLine, _ := runtime.Caller(0)
+	slog.New(NewSlogHandler(scope)).Info("hi") // this is callLine+1
+
+	got := buf.String()
+	want := fmt.Sprintf("slog_test.go:%d:", callLine+1)
+	if !strings.Contains(got, want) {
+		t.Fatalf("expected the caller of Info to be attributed (%s), got %q", want, got)
+	}
+	if strings.Contains(got, "slog.go") {
+		t.Fatalf("record should never be attributed to slog.go itself, got %q", got)
+	}
+}
+
+func TestSlogHandlerWithAttrsGroupOrdering(t *testing.T) {
+	scope := RegisterScope("slog_group_order_test", "")
+	scope.SetOutputLevel(InfoLevel)
+
+	var got []interface{}
+	scope.UseBackend(scopeBackendFunc(func(_ Level, _ string, keysAndVals []interface{}) {
+		got = keysAndVals
+	}))
+
+	// Groups opened after WithAttrs must not retroactively re-prefix the
+	// attrs added before them: "a" was added while only "g1" was open, so
+	// it must come out as "g1.a", not "g1.g2.a".
+	h := NewSlogHandler(scope).WithGroup("g1").WithAttrs([]slog.Attr{slog.String("a", "1")}).WithGroup("g2")
+
+	record := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+	record.AddAttrs(slog.String("b", "2"))
+	if err := h.Handle(context.Background(), record); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	want := []interface{}{"g1.a", "1", "g1.g2.b", "2"}
+	if !equalKV(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// TestSlogHandlerConcurrentNestedGroupIsRaceFree guards against
+// appendSlogAttr mutating a shared groups slice in place: a handler with 3+
+// WithGroup calls behind it has spare capacity in h.groups, so a naive
+// append into a nested slog.Group attr would write into the backing array
+// concurrent Handle calls on the same handler are also reading.
+func TestSlogHandlerConcurrentNestedGroupIsRaceFree(t *testing.T) {
+	scope := RegisterScope("slog_concurrent_group_test", "")
+	scope.SetOutputLevel(InfoLevel)
+	scope.UseBackend(scopeBackendFunc(func(Level, string, []interface{}) {}))
+
+	h := NewSlogHandler(scope).WithGroup("g1").WithGroup("g2").WithGroup("g3")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			record := slog.NewRecord(time.Now(), slog.LevelInfo, "msg", 0)
+			record.AddAttrs(slog.Group("nested", slog.String("k", "v")))
+			if err := h.Handle(context.Background(), record); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func equalKV(got, want []interface{}) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// scopeBackendFunc adapts a plain function to ScopeBackend, for tests that
+// want to capture what a Scope would have emitted.
+type scopeBackendFunc func(level Level, msg string, keysAndVals []interface{})
+
+func (f scopeBackendFunc) Enabled(Level, int) bool { return true }
+func (f scopeBackendFunc) Log(level Level, msg string, keysAndVals []interface{}, _ int) {
+	f(level, msg, keysAndVals)
+}
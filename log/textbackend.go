@@ -0,0 +1,89 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"io"
+
+	"github.com/howardjohn/pkg/log/textlogger"
+)
+
+// ScopeBackend is implemented by alternate logging backends that a Scope can
+// delegate to instead of its default writer. Scope.UseBackend installs one for a given
+// scope. Both methods' skip is the number of additional frames, beyond the
+// backend's own call site, needed to reach the original Debug/Info/Warn/
+// Error call - nonzero only when the Scope has accumulated WithCallDepth
+// offsets - so a backend that attributes records to a caller (e.g.
+// textBackend's Vmodule matching) sees the real one.
+type ScopeBackend interface {
+	Enabled(level Level, skip int) bool
+	Log(level Level, msg string, keysAndVals []interface{}, skip int)
+}
+
+// textBackend adapts a textlogger.Logger, which has its own Level type to
+// avoid an import cycle with this package, to ScopeBackend.
+type textBackend struct {
+	l *textlogger.Logger
+}
+
+// backendCallerSkip accounts for the frames this adapter and Scope itself
+// add on top of whatever skip Scope passes to ScopeBackend: textBackend's
+// own method, Scope.emit, and Scope.Debug/Info/Warn/Error.
+const backendCallerSkip = 3
+
+// enabledCallerSkip is backendCallerSkip plus one: Scope.enabled is reached
+// through the public DebugEnabled/InfoEnabled/WarnEnabled/ErrorEnabled
+// wrappers, an extra frame the Log path doesn't have since Scope.emit calls
+// backend.Log directly.
+const enabledCallerSkip = backendCallerSkip + 1
+
+func (t *textBackend) Enabled(level Level, skip int) bool {
+	return t.l.Enabled(toTextLevel(level), enabledCallerSkip+skip)
+}
+
+func (t *textBackend) Log(level Level, msg string, keysAndVals []interface{}, skip int) {
+	t.l.LogDepth(toTextLevel(level), backendCallerSkip+skip, msg, keysAndVals)
+}
+
+func toTextLevel(level Level) textlogger.Level {
+	switch level {
+	case ErrorLevel:
+		return textlogger.ErrorLevel
+	case WarnLevel:
+		return textlogger.WarnLevel
+	case DebugLevel:
+		return textlogger.DebugLevel
+	default:
+		return textlogger.InfoLevel
+	}
+}
+
+// UseTextBackend switches scope (or, if scope is nil, every registered
+// Scope) to render its output in the klog textual format via
+// log/textlogger, bypassing the Scope's default writer, writing to w.
+// opts.Level, with opts.Vmodule's per-file verbosity overrides, then
+// decides through the Scope's own Enabled checks which records are emitted;
+// the Scope's output level is not consulted while the backend is installed.
+func UseTextBackend(scope *Scope, w io.Writer, opts textlogger.Options) {
+	opts.Writer = w
+	backend := &textBackend{l: textlogger.NewLogger(opts)}
+	if scope != nil {
+		scope.UseBackend(backend)
+		return
+	}
+	for _, s := range Scopes() {
+		s.UseBackend(backend)
+	}
+}
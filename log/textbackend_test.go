@@ -0,0 +1,124 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/howardjohn/pkg/log/textlogger"
+)
+
+func TestUseTextBackend(t *testing.T) {
+	scope := RegisterScope("textbackend_test", "")
+	scope.SetOutputLevel(InfoLevel)
+
+	var buf bytes.Buffer
+	UseTextBackend(scope, &buf, textlogger.Options{Level: textlogger.InfoLevel})
+
+	scope.Info("hello", "key", "val")
+
+	got := buf.String()
+	if !strings.Contains(got, "] hello key=val\n") {
+		t.Fatalf("expected klog-formatted output, got %q", got)
+	}
+
+	buf.Reset()
+	scope.Debug("too verbose")
+	if buf.Len() != 0 {
+		t.Fatalf("expected Debug to be gated out by the backend's Level, got %q", buf.String())
+	}
+}
+
+// TestUseTextBackendAttributesRealCaller guards against the backend
+// reporting its own plumbing (textBackend, Scope.emit, Scope.Debug) as the
+// caller instead of the line that actually called scope.Info.
+func TestUseTextBackendAttributesRealCaller(t *testing.T) {
+	scope := RegisterScope("textbackend_caller_test", "")
+	scope.SetOutputLevel(InfoLevel)
+
+	var buf bytes.Buffer
+	UseTextBackend(scope, &buf, textlogger.Options{Level: textlogger.InfoLevel})
+
+	scope.Info("hello")
+
+	got := buf.String()
+	if strings.Contains(got, "scope.go") {
+		t.Fatalf("expected the real caller, not scope.go, got %q", got)
+	}
+	if !strings.Contains(got, "textbackend_test.go:") {
+		t.Fatalf("expected the caller to be attributed to this file, got %q", got)
+	}
+}
+
+// TestUseTextBackendWithCallDepth proves WithCallDepth threads through the
+// backend path too: a helper wrapping a logging call one frame deep must
+// still be attributed to its own caller once it calls WithCallDepth(1).
+func TestUseTextBackendWithCallDepth(t *testing.T) {
+	scope := RegisterScope("textbackend_call_depth_test", "")
+	scope.SetOutputLevel(InfoLevel)
+
+	var buf bytes.Buffer
+	UseTextBackend(scope, &buf, textlogger.Options{Level: textlogger.InfoLevel})
+
+	_, _, callLine, _ := runtime.Caller(0)
+	logViaWrapper(scope) // this is callLine+1
+
+	got := buf.String()
+	want := fmt.Sprintf("textbackend_test.go:%d]", callLine+1)
+	if !strings.Contains(got, want) {
+		t.Fatalf("expected WithCallDepth(1) to attribute the record to the wrapper's caller (%s), got %q", want, got)
+	}
+}
+
+// TestUseTextBackendVmoduleCanRaiseAboveScopeLevel proves a Vmodule rule can
+// unlock verbosity the Scope's own static level would otherwise block: the
+// backend, not s.level, must be authoritative once one is installed.
+func TestUseTextBackendVmoduleCanRaiseAboveScopeLevel(t *testing.T) {
+	scope := RegisterScope("textbackend_vmodule_test", "")
+	scope.SetOutputLevel(InfoLevel)
+
+	var buf bytes.Buffer
+	UseTextBackend(scope, &buf, textlogger.Options{
+		Level:   textlogger.InfoLevel,
+		Vmodule: "textbackend_test=4",
+	})
+
+	scope.Debug("now visible")
+	if !strings.Contains(buf.String(), "now visible") {
+		t.Fatalf("expected the Vmodule override to unlock Debug for this file despite Scope/backend Level=Info, got %q", buf.String())
+	}
+}
+
+func TestUseTextBackendAllScopes(t *testing.T) {
+	a := RegisterScope("textbackend_test_all_a", "")
+	b := RegisterScope("textbackend_test_all_b", "")
+	a.SetOutputLevel(InfoLevel)
+	b.SetOutputLevel(InfoLevel)
+
+	var buf bytes.Buffer
+	UseTextBackend(nil, &buf, textlogger.Options{Level: textlogger.InfoLevel})
+
+	a.Info("from a")
+	b.Info("from b")
+
+	got := buf.String()
+	if !strings.Contains(got, "from a") || !strings.Contains(got, "from b") {
+		t.Fatalf("expected UseTextBackend(nil, ...) to install the backend on every scope, got %q", got)
+	}
+}
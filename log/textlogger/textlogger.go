@@ -0,0 +1,231 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package textlogger implements a small, dependency-light logger that
+// renders records in the klog textual format, e.g.
+//
+//	I0214 12:34:56.789 file.go:42] msg key=val
+//
+// without depending on Zap. It is intended for unit tests and CLI tools
+// where pulling in Zap's configuration machinery is overkill, and its
+// deterministic output makes it suitable for golden-file testing.
+package textlogger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is this package's notion of severity. It mirrors the parent log
+// package's Debug/Info/Warn/Error levels but is defined independently to
+// avoid an import cycle.
+type Level int
+
+const (
+	ErrorLevel Level = iota
+	WarnLevel
+	InfoLevel
+	DebugLevel
+)
+
+func (l Level) klogChar() byte {
+	switch l {
+	case ErrorLevel:
+		return 'E'
+	case WarnLevel:
+		return 'W'
+	case DebugLevel:
+		return 'D'
+	default:
+		return 'I'
+	}
+}
+
+// Options configures a Logger.
+type Options struct {
+	// Writer is where formatted records are written. Defaults to io.Discard
+	// if nil.
+	Writer io.Writer
+
+	// Level is the default minimum level that is emitted, overridden on a
+	// per-file basis by Vmodule.
+	Level Level
+
+	// Vmodule is a comma-separated list of pattern=level overrides, e.g.
+	// "server=4,controller/*=2". A pattern with no "/" is matched against
+	// the basename of the caller's file (with or without extension), and a
+	// trailing "*" makes it a prefix match on that basename. A pattern
+	// containing "/" matches when the caller's full path ends with it (with
+	// or without ".go"); with a trailing "*", it matches when the full path
+	// contains the part before the "*" anywhere. Later entries take
+	// precedence over earlier ones when more than one pattern matches.
+	Vmodule string
+
+	// Clock, if set, is used in place of time.Now to timestamp records.
+	// Golden-file tests outside this package can set it to a fixed-time
+	// func to get deterministic output.
+	Clock func() time.Time
+}
+
+type vmoduleRule struct {
+	pattern string
+	level   Level
+}
+
+// Logger is a deterministic, allocation-light logger that writes
+// klog-formatted text. It is safe for concurrent use.
+type Logger struct {
+	mu      sync.Mutex
+	w       io.Writer
+	level   Level
+	vmodule []vmoduleRule
+	now     func() time.Time
+}
+
+// NewLogger creates a Logger from opts.
+func NewLogger(opts Options) *Logger {
+	w := opts.Writer
+	if w == nil {
+		w = io.Discard
+	}
+	now := opts.Clock
+	if now == nil {
+		now = time.Now
+	}
+	return &Logger{
+		w:       w,
+		level:   opts.Level,
+		vmodule: parseVmodule(opts.Vmodule),
+		now:     now,
+	}
+}
+
+func parseVmodule(spec string) []vmoduleRule {
+	if spec == "" {
+		return nil
+	}
+	var rules []vmoduleRule
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pattern, levelStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		lvl, err := strconv.Atoi(levelStr)
+		if err != nil {
+			continue
+		}
+		rules = append(rules, vmoduleRule{pattern: pattern, level: Level(lvl)})
+	}
+	return rules
+}
+
+// vmoduleMatch reports whether pattern matches file, a caller's full path.
+// A pattern containing "/" must be a suffix of the full path (".go" may be
+// left off), or, if it ends in "*", the rest of it must occur anywhere in
+// the full path. Otherwise it's matched against just the basename (with or
+// without extension); a trailing "*" then matches as a prefix of the
+// basename.
+func vmoduleMatch(pattern, file string) bool {
+	if strings.Contains(pattern, "/") {
+		if rest, ok := strings.CutSuffix(pattern, "*"); ok {
+			return strings.Contains(file, rest)
+		}
+		return strings.HasSuffix(file, pattern) || strings.HasSuffix(file, pattern+".go")
+	}
+	base := path.Base(file)
+	if rest, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(base, rest)
+	}
+	return pattern == base || pattern == strings.TrimSuffix(base, path.Ext(base))
+}
+
+// levelFor returns the effective level for file, applying the last matching
+// Vmodule rule, or the Logger's default Level if none match.
+func (l *Logger) levelFor(file string) Level {
+	lvl := l.level
+	for _, r := range l.vmodule {
+		if vmoduleMatch(r.pattern, file) {
+			lvl = r.level
+		}
+	}
+	return lvl
+}
+
+// Enabled reports whether level would be emitted for the caller depth
+// frames above its own caller.
+func (l *Logger) Enabled(level Level, depth int) bool {
+	_, file, _, ok := runtime.Caller(depth + 1)
+	if !ok {
+		file = "???"
+	}
+	return level <= l.levelFor(file)
+}
+
+// LogDepth writes msg and keysAndVals at level, attributing the caller
+// depth frames above its own caller. It is a no-op if level is not enabled
+// for that caller's file.
+func (l *Logger) LogDepth(level Level, depth int, msg string, keysAndVals []interface{}) {
+	_, file, line, ok := runtime.Caller(depth + 1)
+	if !ok {
+		file, line = "???", 0
+	}
+	if level > l.levelFor(file) {
+		return
+	}
+
+	var b bytes.Buffer
+	b.WriteByte(level.klogChar())
+	b.WriteString(l.now().Format("0102 15:04:05.000"))
+	b.WriteByte(' ')
+	b.WriteString(path.Base(file))
+	b.WriteByte(':')
+	b.WriteString(strconv.Itoa(line))
+	b.WriteString("] ")
+	b.WriteString(msg)
+	for i := 0; i+1 < len(keysAndVals); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", keysAndVals[i], keysAndVals[i+1])
+	}
+	b.WriteByte('\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.w.Write(b.Bytes())
+}
+
+func (l *Logger) Error(msg string, keysAndVals ...interface{}) {
+	l.LogDepth(ErrorLevel, 1, msg, keysAndVals)
+}
+
+func (l *Logger) Warn(msg string, keysAndVals ...interface{}) {
+	l.LogDepth(WarnLevel, 1, msg, keysAndVals)
+}
+
+func (l *Logger) Info(msg string, keysAndVals ...interface{}) {
+	l.LogDepth(InfoLevel, 1, msg, keysAndVals)
+}
+
+func (l *Logger) Debug(msg string, keysAndVals ...interface{}) {
+	l.LogDepth(DebugLevel, 1, msg, keysAndVals)
+}
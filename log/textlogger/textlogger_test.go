@@ -0,0 +1,104 @@
+// Copyright Istio Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textlogger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLogger(buf *bytes.Buffer, opts Options) *Logger {
+	opts.Writer = buf
+	opts.Clock = func() time.Time { return time.Date(2024, time.February, 14, 12, 34, 56, 789_000_000, time.UTC) }
+	return NewLogger(opts)
+}
+
+func TestLoggerFormat(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, Options{Level: InfoLevel})
+
+	l.Info("hello", "key", "val")
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "I0214 12:34:56.789 ") {
+		t.Fatalf("unexpected prefix: %q", got)
+	}
+	if !strings.Contains(got, "textlogger_test.go:") {
+		t.Fatalf("expected caller file in output: %q", got)
+	}
+	if !strings.HasSuffix(got, "] hello key=val\n") {
+		t.Fatalf("unexpected suffix: %q", got)
+	}
+}
+
+func TestLoggerLevelGating(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, Options{Level: InfoLevel})
+
+	l.Debug("too verbose")
+	if buf.Len() != 0 {
+		t.Fatalf("expected Debug to be gated out, got %q", buf.String())
+	}
+
+	l.Error("shown")
+	if buf.Len() == 0 {
+		t.Fatalf("expected Error to be emitted")
+	}
+}
+
+func TestVmoduleOverride(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, Options{Level: InfoLevel, Vmodule: "textlogger_test=4"})
+
+	l.Debug("now visible")
+	if !strings.Contains(buf.String(), "now visible") {
+		t.Fatalf("expected Vmodule override to enable Debug for this file, got %q", buf.String())
+	}
+}
+
+// TestVmoduleOverrideDirectoryPattern covers the directory-qualified form
+// of Vmodule (e.g. "pkg/*=2") called out in the Options doc comment, which
+// must be matched against the caller's full path rather than its basename.
+func TestVmoduleOverrideDirectoryPattern(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, Options{Level: InfoLevel, Vmodule: "textlogger/*=4"})
+
+	l.Debug("now visible via directory pattern")
+	if !strings.Contains(buf.String(), "now visible via directory pattern") {
+		t.Fatalf("expected directory-qualified Vmodule pattern to enable Debug for this file, got %q", buf.String())
+	}
+}
+
+func TestVmoduleMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{pattern: "server", file: "/repo/pkg/server.go", want: true},
+		{pattern: "server", file: "/repo/pkg/serverutil.go", want: false},
+		{pattern: "serv*", file: "/repo/pkg/server.go", want: true},
+		{pattern: "controller/*", file: "/repo/pkg/controller/watch.go", want: true},
+		{pattern: "controller/*", file: "/repo/pkg/server.go", want: false},
+		{pattern: "pkg/server.go", file: "/repo/pkg/server.go", want: true},
+	}
+	for _, tt := range tests {
+		if got := vmoduleMatch(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("vmoduleMatch(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}